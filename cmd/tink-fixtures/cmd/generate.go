@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	testutils "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/test/utils"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate Tinkerbell fixtures without pushing them anywhere",
+}
+
+var generateHardwareCmd = &cobra.Command{
+	Use:   "hardware",
+	Short: "Generate a Hardware fixture with randomized MACs and the next free IPs",
+	RunE:  runGenerateHardware,
+}
+
+var generateTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Generate a WorkflowTemplate fixture from a YAML file",
+	RunE:  runGenerateTemplate,
+}
+
+func init() {
+	generateHardwareCmd.Flags().Int("interfaces", 1, "Number of network interfaces to generate")
+	generateHardwareCmd.Flags().String("cidr", "", "CIDR to draw interface addresses from (cycles through built-in test CIDRs if unset)")
+
+	generateTemplateCmd.Flags().String("name", "", "Name of the generated template")
+	generateTemplateCmd.Flags().String("file", "", "Path to the template's YAML body")
+
+	if err := generateTemplateCmd.MarkFlagRequired("name"); err != nil {
+		panic(err)
+	}
+
+	if err := generateTemplateCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	generateCmd.AddCommand(generateHardwareCmd)
+	generateCmd.AddCommand(generateTemplateCmd)
+}
+
+func runGenerateHardware(cmd *cobra.Command, _ []string) error {
+	interfaces, err := cmd.Flags().GetInt("interfaces")
+	if err != nil {
+		return err
+	}
+
+	cidr, err := cmd.Flags().GetString("cidr")
+	if err != nil {
+		return err
+	}
+
+	hw, err := testutils.GenerateHardware(interfaces, cidr)
+	if err != nil {
+		return fmt.Errorf("failed to generate hardware: %w", err)
+	}
+
+	return printJSON(cmd, hw)
+}
+
+func runGenerateTemplate(cmd *cobra.Command, _ []string) error {
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return err
+	}
+
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	return printJSON(cmd, testutils.GenerateTemplate(name, string(data)))
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+
+	cmd.Println(string(out))
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "tink-fixtures",
+	Short: "Generate and seed Tinkerbell test fixtures",
+}
+
+func init() {
+	viper.SetEnvPrefix("TINK")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	rootCmd.PersistentFlags().String("grpc-authority", "", "Address of tink-server's gRPC API (env TINK_GRPC_AUTHORITY)")
+	rootCmd.PersistentFlags().String("cert-url", "", "URL to fetch tink-server's TLS certificate from (env TINK_CERT_URL)")
+
+	for _, name := range []string{"grpc-authority", "cert-url"} {
+		if err := viper.BindPFlag(name, rootCmd.PersistentFlags().Lookup(name)); err != nil {
+			panic(fmt.Sprintf("failed to bind flag %q: %s", name, err))
+		}
+	}
+
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(seedCmd)
+}
+
+// Execute runs the tink-fixtures root command.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
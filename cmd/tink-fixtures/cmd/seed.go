@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/tinkclient"
+	testutils "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/test/utils"
+	"github.com/tinkerbell/tink/protos/hardware"
+	"github.com/tinkerbell/tink/protos/template"
+	"github.com/tinkerbell/tink/protos/workflow"
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Push generated Hardware, Template, and Workflow fixtures to a live tink-server",
+	RunE:  runSeed,
+}
+
+func init() {
+	seedCmd.Flags().Int("count", 1, "Number of hardware/workflow pairs to seed")
+}
+
+func runSeed(cmd *cobra.Command, _ []string) error {
+	count, err := cmd.Flags().GetInt("count")
+	if err != nil {
+		return err
+	}
+
+	grpcAuthority := viper.GetString("grpc-authority")
+	certURL := viper.GetString("cert-url")
+
+	if grpcAuthority == "" || certURL == "" {
+		return fmt.Errorf("--grpc-authority and --cert-url (or TINK_GRPC_AUTHORITY/TINK_CERT_URL) are required")
+	}
+
+	conn, err := tinkclient.Dial(certURL, grpcAuthority)
+	if err != nil {
+		return err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	ctx := context.Background()
+	hardwareClient := tinkclient.HardwareClient(conn)
+	templateClient := tinkclient.TemplateClient(conn)
+	workflowClient := tinkclient.WorkflowClient(conn)
+
+	templateResp, err := templateClient.Create(ctx, testutils.GenerateTemplate("tink-fixtures-hello-world", testutils.HelloWorldTemplate))
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	for i := 0; i < count; i++ {
+		hw, err := testutils.GenerateHardware(1, "")
+		if err != nil {
+			return fmt.Errorf("failed to generate hardware: %w", err)
+		}
+
+		hw.Id = uuid.New().String()
+
+		if _, err := hardwareClient.Push(ctx, &hardware.PushRequest{Data: hw}); err != nil {
+			return fmt.Errorf("failed to push hardware: %w", err)
+		}
+
+		workflowResp, err := workflowClient.CreateWorkflow(ctx, &workflow.CreateRequest{
+			Template: templateResp.GetId(),
+			Hardware: hw.GetId(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create workflow: %w", err)
+		}
+
+		cmd.Printf("seeded hardware %s with workflow %s\n", hw.GetId(), workflowResp.GetId())
+	}
+
+	return nil
+}
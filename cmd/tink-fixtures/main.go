@@ -0,0 +1,27 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command tink-fixtures generates and seeds Tinkerbell test fixtures,
+// turning the library helpers under tink/test/utils into an operator
+// tool for smoke-testing a fresh cluster-api-provider-tinkerbell
+// deployment.
+package main
+
+import "github.com/tinkerbell/cluster-api-provider-tinkerbell/cmd/tink-fixtures/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HardwareFinalizer is added to every Hardware so its Tinkerbell-side
+// resources, including any addresses allocated from a TinkerbellIPPool,
+// are cleaned up before the object is removed.
+const HardwareFinalizer = "hardware.tinkerbell.org"
+
+// HardwareInterface requests an address from Pool for MAC.
+type HardwareInterface struct {
+	// MAC is the hardware address of the interface to allocate for.
+	MAC string `json:"mac"`
+
+	// Pool is the name of the TinkerbellIPPool to allocate MAC's address
+	// from.
+	Pool string `json:"pool"`
+}
+
+// HardwareSpec defines the desired state of a piece of Tinkerbell
+// Hardware.
+type HardwareSpec struct {
+	// ID is the Tinkerbell-side hardware ID, used when creating workflows
+	// against this Hardware.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Interfaces are the network interfaces that should be allocated
+	// addresses from a TinkerbellIPPool.
+	// +optional
+	Interfaces []HardwareInterface `json:"interfaces,omitempty"`
+}
+
+// HardwareInterfaceStatus is the address allocated for a HardwareInterface.
+type HardwareInterfaceStatus struct {
+	MAC     string `json:"mac"`
+	IP      string `json:"ip"`
+	Netmask string `json:"netmask,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// HardwareStatus defines the observed state of a piece of Tinkerbell
+// Hardware.
+type HardwareStatus struct {
+	// Interfaces records the addresses allocated for Spec.Interfaces.
+	// +optional
+	Interfaces []HardwareInterfaceStatus `json:"interfaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Hardware is the Schema for the hardware API.
+type Hardware struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HardwareSpec   `json:"spec,omitempty"`
+	Status HardwareStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HardwareList contains a list of Hardware.
+type HardwareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Hardware `json:"items"`
+}
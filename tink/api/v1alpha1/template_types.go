@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TemplateSpec defines the desired state of a Tinkerbell workflow
+// template.
+type TemplateSpec struct {
+	// Data is the raw, unrendered YAML body of the template, using
+	// "{{.foo}}" placeholders for per-workflow values.
+	Data string `json:"data"`
+
+	// Parameters are default values for Data's placeholders, used when a
+	// Workflow referencing this Template doesn't set its own
+	// Spec.Parameters entry of the same name.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// TemplateStatus defines the observed state of a Tinkerbell workflow
+// template.
+type TemplateStatus struct{}
+
+// +kubebuilder:object:root=true
+
+// Template is the Schema for the templates API.
+type Template struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TemplateSpec   `json:"spec,omitempty"`
+	Status TemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TemplateList contains a list of Template.
+type TemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Template `json:"items"`
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TinkerbellIPPoolSpec describes a range of addresses that Hardware
+// interfaces are allocated from.
+type TinkerbellIPPoolSpec struct {
+	// CIDR is the network range this pool allocates addresses from, e.g.
+	// "192.168.1.0/24" or "fd00::/64".
+	CIDR string `json:"cidr"`
+
+	// Gateway is reserved and never handed out by Allocate.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// DNS servers advertised alongside addresses allocated from this pool.
+	// +optional
+	DNS []string `json:"dns,omitempty"`
+
+	// ExcludeRanges are CIDRs within CIDR that Allocate must skip, e.g.
+	// addresses reserved for DHCP or already handed out by another system.
+	// +optional
+	ExcludeRanges []string `json:"excludeRanges,omitempty"`
+
+	// Reservations pins specific MAC addresses to specific IPs, bypassing
+	// the normal allocation walk.
+	// +optional
+	Reservations map[string]string `json:"reservations,omitempty"`
+}
+
+// Allocation records a single address handed out from a TinkerbellIPPool.
+type Allocation struct {
+	// MAC is the hardware address the address was allocated to.
+	MAC string `json:"mac"`
+
+	// IP is the allocated address.
+	IP string `json:"ip"`
+}
+
+// TinkerbellIPPoolStatus records the addresses that have been handed out
+// from this pool.
+type TinkerbellIPPoolStatus struct {
+	// Allocated is the set of addresses currently in use.
+	// +optional
+	Allocated []Allocation `json:"allocated,omitempty"`
+
+	// Available is the number of unallocated addresses remaining in CIDR,
+	// after ExcludeRanges, Reservations, and the network, gateway and
+	// broadcast addresses are removed.
+	// +optional
+	Available int `json:"available,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TinkerbellIPPool is the Schema for the tinkerbellippools API.
+type TinkerbellIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TinkerbellIPPoolSpec   `json:"spec,omitempty"`
+	Status TinkerbellIPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TinkerbellIPPoolList contains a list of TinkerbellIPPool.
+type TinkerbellIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TinkerbellIPPool `json:"items"`
+}
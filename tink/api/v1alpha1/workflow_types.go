@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowFinalizer is added to every Workflow so its Tinkerbell-side
+// workflow is deleted from tink-server before the object is removed.
+const WorkflowFinalizer = "workflow.tinkerbell.org"
+
+// tinkIDAnnotation records the Tinkerbell-side ID assigned to a Workflow
+// once it has been created against tink-server. It's stored as an
+// annotation, rather than a spec/status field, since it's an
+// implementation detail of how this Workflow maps onto tink-server and
+// not something a user sets or needs to read.
+const tinkIDAnnotation = "workflow.tinkerbell.org/tink-id"
+
+// WorkflowSpec defines the desired state of a Tinkerbell workflow.
+type WorkflowSpec struct {
+	// HardwareRef is the name of the Hardware this workflow runs against.
+	HardwareRef string `json:"hardwareRef"`
+
+	// TemplateRef is the name of the Template this workflow renders and
+	// executes.
+	TemplateRef string `json:"templateRef"`
+
+	// Parameters are substituted into TemplateRef's "{{.foo}}"
+	// placeholders, taking precedence over any value of the same name
+	// set in the Template's own Spec.Parameters.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Action is the observed state of a single step of a running workflow.
+type Action struct {
+	Name        string            `json:"name"`
+	TaskName    string            `json:"taskName"`
+	Image       string            `json:"image"`
+	Timeout     int64             `json:"timeout"`
+	Command     []string          `json:"command,omitempty"`
+	OnTimeout   []string          `json:"onTimeout,omitempty"`
+	OnFailure   []string          `json:"onFailure,omitempty"`
+	WorkerID    string            `json:"workerId,omitempty"`
+	Volumes     []string          `json:"volumes,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// Event is a single reported status change of a workflow action.
+type Event struct {
+	ActionName   string      `json:"actionName"`
+	TaskName     string      `json:"taskName"`
+	ActionStatus string      `json:"actionStatus"`
+	Seconds      int64       `json:"seconds"`
+	Message      string      `json:"message,omitempty"`
+	WorkerID     string      `json:"workerId,omitempty"`
+	CreatedAt    metav1.Time `json:"createdAt"`
+}
+
+// WorkflowStatus defines the observed state of a Tinkerbell workflow.
+type WorkflowStatus struct {
+	// Data is the raw workflow data tink-server reports back for this
+	// workflow.
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// Metadata is the raw, tink-server-reported metadata for this
+	// workflow.
+	// +optional
+	Metadata string `json:"metadata,omitempty"`
+
+	// RenderedTemplate is the fully rendered YAML registered with
+	// tink-server for this workflow, with all Parameters substituted in.
+	// +optional
+	RenderedTemplate string `json:"renderedTemplate,omitempty"`
+
+	// Actions mirrors tink-server's view of this workflow's steps.
+	// +optional
+	Actions []Action `json:"actions,omitempty"`
+
+	// Events mirrors tink-server's reported status changes for this
+	// workflow's actions.
+	// +optional
+	Events []Event `json:"events,omitempty"`
+
+	// Conditions track Ready/Progressing/Failed for this workflow.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Workflow is the Schema for the workflows API.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowSpec   `json:"spec,omitempty"`
+	Status WorkflowStatus `json:"status,omitempty"`
+}
+
+// TinkID returns the Tinkerbell-side ID recorded for this workflow, or ""
+// if it hasn't been created against tink-server yet.
+func (w *Workflow) TinkID() string {
+	return w.Annotations[tinkIDAnnotation]
+}
+
+// SetTinkID records id as this workflow's Tinkerbell-side ID.
+func (w *Workflow) SetTinkID(id string) {
+	if w.Annotations == nil {
+		w.Annotations = make(map[string]string, 1)
+	}
+
+	w.Annotations[tinkIDAnnotation] = id
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowList contains a list of Workflow.
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Workflow `json:"items"`
+}
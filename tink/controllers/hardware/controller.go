@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllers contains controllers for Tinkerbell.
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/controllers/common"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/ipam"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Reconciler implements Reconciler interface by allocating and releasing
+// Tinkerbell Hardware addresses.
+type Reconciler struct {
+	client.Client
+	Allocator *ipam.Allocator
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+}
+
+// SetupWithManager configures reconciler with a given manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tinkv1alpha1.Hardware{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=hardware;hardware/status,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile ensures state of Tinkerbell Hardware.
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	logger := r.Log.WithValues("hardware", req.NamespacedName.Name)
+
+	hw := &tinkv1alpha1.Hardware{}
+	if err := r.Get(ctx, req.NamespacedName, hw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		logger.Error(err, "Failed to get hardware")
+
+		return ctrl.Result{}, fmt.Errorf("failed to get hardware: %w", err)
+	}
+
+	if err := common.EnsureFinalizer(ctx, r.Client, logger, hw, tinkv1alpha1.HardwareFinalizer); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure finalizer on hardware: %w", err)
+	}
+
+	if !hw.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, hw)
+	}
+
+	return r.reconcileNormal(ctx, hw)
+}
+
+func (r *Reconciler) reconcileNormal(ctx context.Context, hw *tinkv1alpha1.Hardware) (ctrl.Result, error) {
+	logger := r.Log.WithValues("hardware", hw.Name)
+	patch := client.MergeFrom(hw.DeepCopy())
+
+	for _, iface := range hw.Spec.Interfaces {
+		if hasAllocation(hw, iface.MAC) {
+			continue
+		}
+
+		allocation, err := r.Allocator.Allocate(ctx, iface.Pool, iface.MAC)
+		if err != nil {
+			logger.Error(err, "Failed to allocate address", "mac", iface.MAC, "pool", iface.Pool)
+
+			return ctrl.Result{}, fmt.Errorf("failed to allocate address for %s from pool %s: %w", iface.MAC, iface.Pool, err)
+		}
+
+		hw.Status.Interfaces = append(hw.Status.Interfaces, tinkv1alpha1.HardwareInterfaceStatus{
+			MAC:     allocation.MAC,
+			IP:      allocation.IP,
+			Netmask: allocation.Netmask,
+			Gateway: allocation.Gateway,
+		})
+	}
+
+	if err := r.Client.Status().Patch(ctx, hw, patch); err != nil {
+		logger.Error(err, "Failed to patch hardware status")
+
+		return ctrl.Result{}, fmt.Errorf("failed to patch hardware status: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) reconcileDelete(ctx context.Context, hw *tinkv1alpha1.Hardware) (ctrl.Result, error) {
+	logger := r.Log.WithValues("hardware", hw.Name)
+	patch := client.MergeFrom(hw.DeepCopy())
+
+	for _, iface := range hw.Spec.Interfaces {
+		if err := r.Allocator.Release(ctx, iface.MAC); err != nil {
+			logger.Error(err, "Failed to release address", "mac", iface.MAC)
+
+			return ctrl.Result{}, fmt.Errorf("failed to release address for %s: %w", iface.MAC, err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(hw, tinkv1alpha1.HardwareFinalizer)
+
+	if err := r.Client.Patch(ctx, hw, patch); err != nil {
+		logger.Error(err, "Failed to patch hardware")
+
+		return ctrl.Result{}, fmt.Errorf("failed to patch hardware: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func hasAllocation(hw *tinkv1alpha1.Hardware, mac string) bool {
+	for _, s := range hw.Status.Interfaces {
+		if s.MAC == mac {
+			return true
+		}
+	}
+
+	return false
+}
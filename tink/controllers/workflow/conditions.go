@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/protos/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported on Workflow.Status.Conditions.
+const (
+	// WorkflowReadyCondition is true once the workflow has finished
+	// successfully.
+	WorkflowReadyCondition = "Ready"
+	// WorkflowProgressingCondition is true while tink-server is still
+	// executing the workflow.
+	WorkflowProgressingCondition = "Progressing"
+	// WorkflowFailedCondition is true when the workflow ended in
+	// STATE_FAILED or STATE_TIMEOUT.
+	WorkflowFailedCondition = "Failed"
+)
+
+// setStateConditions derives Ready/Progressing/Failed from the workflow's
+// Tinkerbell state, using the most recent event's message as the condition
+// message when one is available.
+func setStateConditions(w *tinkv1alpha1.Workflow, state workflow.State, events []tinkv1alpha1.Event) {
+	message := state.String()
+	if n := len(events); n > 0 {
+		message = events[n-1].Message
+	}
+
+	switch state {
+	case workflow.State_STATE_SUCCESS:
+		setCondition(w, WorkflowReadyCondition, metav1.ConditionTrue, state.String(), message)
+		setCondition(w, WorkflowProgressingCondition, metav1.ConditionFalse, state.String(), message)
+		setCondition(w, WorkflowFailedCondition, metav1.ConditionFalse, state.String(), message)
+	case workflow.State_STATE_FAILED, workflow.State_STATE_TIMEOUT:
+		setCondition(w, WorkflowReadyCondition, metav1.ConditionFalse, state.String(), message)
+		setCondition(w, WorkflowProgressingCondition, metav1.ConditionFalse, state.String(), message)
+		setCondition(w, WorkflowFailedCondition, metav1.ConditionTrue, state.String(), message)
+	default:
+		setCondition(w, WorkflowReadyCondition, metav1.ConditionFalse, state.String(), message)
+		setCondition(w, WorkflowProgressingCondition, metav1.ConditionTrue, state.String(), message)
+		setCondition(w, WorkflowFailedCondition, metav1.ConditionFalse, state.String(), message)
+	}
+}
+
+// setCondition upserts a condition on w.Status.Conditions, only bumping
+// LastTransitionTime when the status actually flips.
+func setCondition(w *tinkv1alpha1.Workflow, condType string, status metav1.ConditionStatus, reason, message string) {
+	for i := range w.Status.Conditions {
+		c := &w.Status.Conditions[i]
+		if c.Type != condType {
+			continue
+		}
+
+		if c.Status != status {
+			c.LastTransitionTime = metav1.Now()
+		}
+
+		c.Status = status
+		c.Reason = reason
+		c.Message = message
+
+		return
+	}
+
+	w.Status.Conditions = append(w.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// progressingReason returns the Reason recorded on the Progressing
+// condition, which we use as a stand-in for "previously observed state"
+// since Workflow no longer has a flat status string. It returns "" if the
+// condition hasn't been set yet.
+func progressingReason(w *tinkv1alpha1.Workflow) string {
+	for _, c := range w.Status.Conditions {
+		if c.Type == WorkflowProgressingCondition {
+			return c.Reason
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/protos/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSetStateConditionsRunning(t *testing.T) {
+	g := NewWithT(t)
+
+	w := &tinkv1alpha1.Workflow{}
+	setStateConditions(w, workflow.State_STATE_RUNNING, nil)
+
+	g.Expect(progressingReason(w)).To(Equal(workflow.State_STATE_RUNNING.String()))
+
+	ready := findCondition(w, WorkflowReadyCondition)
+	g.Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+
+	progressing := findCondition(w, WorkflowProgressingCondition)
+	g.Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+
+	failed := findCondition(w, WorkflowFailedCondition)
+	g.Expect(failed.Status).To(Equal(metav1.ConditionFalse))
+}
+
+func TestSetStateConditionsSuccess(t *testing.T) {
+	g := NewWithT(t)
+
+	w := &tinkv1alpha1.Workflow{}
+	setStateConditions(w, workflow.State_STATE_SUCCESS, nil)
+
+	g.Expect(findCondition(w, WorkflowReadyCondition).Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(findCondition(w, WorkflowProgressingCondition).Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(findCondition(w, WorkflowFailedCondition).Status).To(Equal(metav1.ConditionFalse))
+}
+
+func TestSetStateConditionsFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	w := &tinkv1alpha1.Workflow{}
+	setStateConditions(w, workflow.State_STATE_FAILED, nil)
+
+	g.Expect(findCondition(w, WorkflowReadyCondition).Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(findCondition(w, WorkflowProgressingCondition).Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(findCondition(w, WorkflowFailedCondition).Status).To(Equal(metav1.ConditionTrue))
+}
+
+func TestSetConditionOnlyBumpsTransitionTimeOnStatusChange(t *testing.T) {
+	g := NewWithT(t)
+
+	w := &tinkv1alpha1.Workflow{}
+
+	setCondition(w, WorkflowReadyCondition, metav1.ConditionFalse, "Running", "still running")
+	first := findCondition(w, WorkflowReadyCondition).LastTransitionTime
+
+	// Same status, different message: LastTransitionTime must not move.
+	setCondition(w, WorkflowReadyCondition, metav1.ConditionFalse, "Running", "still running, take two")
+	g.Expect(findCondition(w, WorkflowReadyCondition).LastTransitionTime).To(Equal(first))
+	g.Expect(findCondition(w, WorkflowReadyCondition).Message).To(Equal("still running, take two"))
+
+	// Status flips: LastTransitionTime must be allowed to move.
+	setCondition(w, WorkflowReadyCondition, metav1.ConditionTrue, "Success", "done")
+	g.Expect(findCondition(w, WorkflowReadyCondition).Status).To(Equal(metav1.ConditionTrue))
+}
+
+func TestProgressingReasonUnset(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(progressingReason(&tinkv1alpha1.Workflow{})).To(Equal(""))
+}
+
+func findCondition(w *tinkv1alpha1.Workflow, condType string) *metav1.Condition {
+	for i := range w.Status.Conditions {
+		if w.Status.Conditions[i].Type == condType {
+			return &w.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
@@ -21,24 +21,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
 	tinkclient "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/client"
 	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/controllers/common"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/templates"
 	"github.com/tinkerbell/tink/protos/workflow"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const workflowControllerName = "tinkerbell-workflow-controller"
+
+// minBackoff and maxBackoff bound the exponential backoff used when no
+// shared Watcher is registered to notify us of workflow changes.
+const (
+	minBackoff = 30 * time.Second
+	maxBackoff = 5 * time.Minute
+)
+
 type workflowClient interface {
 	Get(ctx context.Context, id string) (*workflow.Workflow, error)
 	Create(ctx context.Context, templateID, hardwareID string) (string, error)
@@ -49,16 +63,38 @@ type workflowClient interface {
 	GetState(ctx context.Context, id string) (workflow.State, error)
 }
 
+// templateClient registers the rendered, per-workflow template with
+// tink-server so its returned ID can be handed to workflowClient.Create.
+type templateClient interface {
+	Create(ctx context.Context, name, data string) (string, error)
+}
+
 // Reconciler implements Reconciler interface by managing Tinkerbell workflows.
 type Reconciler struct {
 	client.Client
 	WorkflowClient workflowClient
-	Log            logr.Logger
-	Scheme         *runtime.Scheme
+	TemplateClient templateClient
+	Recorder       record.EventRecorder
+	// Watcher is the shared gRPC watcher started from SetupWithManager. It
+	// is nil when the Reconciler is used without SetupWithManager (e.g. in
+	// tests), in which case reconcileStatus falls back to self-requeuing.
+	Watcher *Watcher
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+
+	backoffMu sync.Mutex
+	backoff   map[string]time.Duration
 }
 
 // SetupWithManager configures reconciler with a given manager.
-func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workflowChan <-chan event.GenericEvent) error {
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workflowChan chan event.GenericEvent) error {
+	r.Recorder = mgr.GetEventRecorderFor(workflowControllerName)
+	r.Watcher = NewWatcher(r.WorkflowClient, workflowChan, r.Log.WithName("watcher"))
+
+	if err := mgr.Add(manager.RunnableFunc(r.Watcher.Start)); err != nil {
+		return fmt.Errorf("failed to register workflow watcher: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&tinkv1alpha1.Workflow{}).
 		Watches(
@@ -116,6 +152,8 @@ func (r *Reconciler) reconcileNormal(ctx context.Context, w *tinkv1alpha1.Workfl
 
 	tinkWorkflow, err := r.WorkflowClient.Get(ctx, workflowID)
 	if err != nil {
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to get workflow: %s", err)
+
 		return ctrl.Result{}, fmt.Errorf("failed to get workflow: %w", err)
 	}
 
@@ -148,6 +186,7 @@ func (r *Reconciler) reconcileStatus(ctx context.Context, w *tinkv1alpha1.Workfl
 	md, err := r.WorkflowClient.GetMetadata(ctx, tinkWorkflow.GetId())
 	if err != nil {
 		logger.Error(err, "Failed to get metadata for workflow")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to get metadata for workflow: %s", err)
 
 		return ctrl.Result{}, fmt.Errorf("failed to get metadata for workflow: %w", err)
 	}
@@ -157,6 +196,7 @@ func (r *Reconciler) reconcileStatus(ctx context.Context, w *tinkv1alpha1.Workfl
 	actions, err := r.WorkflowClient.GetActions(ctx, tinkWorkflow.GetId())
 	if err != nil {
 		logger.Error(err, "Failed to get actions for workflow")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to get actions for workflow: %s", err)
 
 		return ctrl.Result{}, fmt.Errorf("failed to get actions for workflow: %w", err)
 	}
@@ -182,6 +222,7 @@ func (r *Reconciler) reconcileStatus(ctx context.Context, w *tinkv1alpha1.Workfl
 	events, err := r.WorkflowClient.GetEvents(ctx, tinkWorkflow.GetId())
 	if err != nil {
 		logger.Error(err, "Failed to get events for workflow")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to get events for workflow: %s", err)
 
 		return ctrl.Result{}, fmt.Errorf("failed to get events for workflow: %w", err)
 	}
@@ -204,22 +245,90 @@ func (r *Reconciler) reconcileStatus(ctx context.Context, w *tinkv1alpha1.Workfl
 	state, err := r.WorkflowClient.GetState(ctx, tinkWorkflow.GetId())
 	if err != nil {
 		logger.Error(err, "Failed to get state for workflow")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to get state for workflow: %s", err)
 
 		return ctrl.Result{}, fmt.Errorf("failed to get state for workflow: %w", err)
 	}
 
-	w.Status.State = state.String()
+	previousState := progressingReason(w)
+	changed := previousState != state.String()
+
+	setStateConditions(w, state, statusEvents)
+
+	if changed {
+		r.Recorder.Eventf(w, corev1.EventTypeNormal, "StateChanged", "workflow state changed from %q to %q", previousState, state)
+	}
+
+	if changed {
+		switch state {
+		case workflow.State_STATE_SUCCESS:
+			r.Recorder.Event(w, corev1.EventTypeNormal, "Succeeded", "workflow completed successfully")
+		case workflow.State_STATE_FAILED, workflow.State_STATE_TIMEOUT:
+			r.Recorder.Eventf(w, corev1.EventTypeWarning, "Failed", "workflow ended in state %q", state)
+		}
+	}
+
+	terminal := state == workflow.State_STATE_SUCCESS || state == workflow.State_STATE_FAILED || state == workflow.State_STATE_TIMEOUT
+
+	// With a shared Watcher registered, it owns telling us when this
+	// workflow's state actually changes, so there's no need to requeue
+	// ourselves. Once the workflow reaches a terminal state it will never
+	// change again, so stop polling it.
+	if r.Watcher != nil {
+		if terminal {
+			r.Watcher.Untrack(tinkWorkflow.GetId())
+		} else {
+			r.Watcher.Track(tinkWorkflow.GetId(), client.ObjectKeyFromObject(w))
+		}
+
+		return ctrl.Result{}, nil
+	}
 
 	if state != workflow.State_STATE_SUCCESS {
-		// If the workflow hasn't successfully run, requeue in
-		// a minute. This is to workaround the lack of events
-		// for workflow status
-		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		return ctrl.Result{RequeueAfter: r.nextBackoff(w.Name, changed)}, nil
 	}
 
+	r.clearBackoff(w.Name)
+
 	return ctrl.Result{}, nil
 }
 
+// nextBackoff returns the delay to requeue w after, doubling from
+// minBackoff up to maxBackoff each time the workflow's state is
+// unchanged, and resetting to minBackoff whenever it changes.
+func (r *Reconciler) nextBackoff(name string, changed bool) time.Duration {
+	r.backoffMu.Lock()
+	defer r.backoffMu.Unlock()
+
+	if r.backoff == nil {
+		r.backoff = make(map[string]time.Duration)
+	}
+
+	if changed {
+		r.backoff[name] = minBackoff
+
+		return minBackoff
+	}
+
+	next := r.backoff[name] * 2
+	if next < minBackoff {
+		next = minBackoff
+	} else if next > maxBackoff {
+		next = maxBackoff
+	}
+
+	r.backoff[name] = next
+
+	return next
+}
+
+func (r *Reconciler) clearBackoff(name string) {
+	r.backoffMu.Lock()
+	defer r.backoffMu.Unlock()
+
+	delete(r.backoff, name)
+}
+
 func (r *Reconciler) createWorkflow(ctx context.Context, w *tinkv1alpha1.Workflow) (string, error) {
 	logger := r.Log.WithValues("workflow", w.Name)
 
@@ -241,19 +350,85 @@ func (r *Reconciler) createWorkflow(ctx context.Context, w *tinkv1alpha1.Workflo
 		return "", fmt.Errorf("failed to get template: %w", err)
 	}
 
+	templateID, err := r.renderTemplate(ctx, w, t)
+	if err != nil {
+		return "", err
+	}
+
 	id, err := r.WorkflowClient.Create(
-		ctx, t.TinkID(),
+		ctx, templateID,
 		hw.Spec.ID,
 	)
 	if err != nil {
 		logger.Error(err, "Failed to create workflow")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to create workflow: %s", err)
 
 		return "", fmt.Errorf("failed to create workflow: %w", err)
 	}
 
+	r.Recorder.Eventf(w, corev1.EventTypeNormal, "Created", "created Tinkerbell workflow %s", id)
+
 	return id, nil
 }
 
+// renderTemplate merges t's default parameters with w.Spec.Parameters,
+// substitutes them into t's raw YAML, confirms the result parses as a
+// valid TemplateSpec, registers it with tink-server as a new template,
+// and returns that template's ID for use by WorkflowClient.Create. The
+// result is also surfaced on Workflow.Status.RenderedTemplate so
+// operators can see exactly what tink-server received.
+func (r *Reconciler) renderTemplate(ctx context.Context, w *tinkv1alpha1.Workflow, t *tinkv1alpha1.Template) (string, error) {
+	logger := r.Log.WithValues("workflow", w.Name)
+
+	params := make(map[string]string, len(t.Spec.Parameters)+len(w.Spec.Parameters))
+	for k, v := range t.Spec.Parameters {
+		params[k] = v
+	}
+
+	for k, v := range w.Spec.Parameters {
+		params[k] = v
+	}
+
+	if missing := templates.MissingParameters(t.Spec.Data, params); len(missing) > 0 {
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "MissingParameter", "template %q is missing values for: %v", t.Name, missing)
+
+		return "", fmt.Errorf("template %q is missing values for: %v", t.Name, missing)
+	}
+
+	rendered, err := templates.Render(t.Spec.Data, params)
+	if err != nil {
+		logger.Error(err, "Failed to render template")
+
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	if _, err := templates.Parse(rendered); err != nil {
+		logger.Error(err, "Rendered template is not valid")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "InvalidTemplate", "rendered template %q is not valid: %s", t.Name, err)
+
+		return "", fmt.Errorf("rendered template %q is not valid: %w", t.Name, err)
+	}
+
+	patch := client.MergeFrom(w.DeepCopy())
+	w.Status.RenderedTemplate = rendered
+
+	if err := r.Client.Status().Patch(ctx, w, patch); err != nil {
+		logger.Error(err, "Failed to patch workflow status with rendered template")
+
+		return "", fmt.Errorf("failed to patch workflow status with rendered template: %w", err)
+	}
+
+	templateID, err := r.TemplateClient.Create(ctx, fmt.Sprintf("%s-%s", t.Name, w.Name), rendered)
+	if err != nil {
+		logger.Error(err, "Failed to create rendered template")
+		r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to create rendered template: %s", err)
+
+		return "", fmt.Errorf("failed to create rendered template: %w", err)
+	}
+
+	return templateID, nil
+}
+
 func (r *Reconciler) reconcileDelete(ctx context.Context, w *tinkv1alpha1.Workflow) (ctrl.Result, error) {
 	// Create a patch for use later
 	patch := client.MergeFrom(w.DeepCopy())
@@ -265,9 +440,14 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, w *tinkv1alpha1.Workfl
 		err := r.WorkflowClient.Delete(ctx, id)
 		if err != nil && !errors.Is(err, tinkclient.ErrNotFound) {
 			logger.Error(err, "Failed to delete workflow from Tinkerbell")
+			r.Recorder.Eventf(w, corev1.EventTypeWarning, "TinkAPIError", "failed to delete workflow from Tinkerbell: %s", err)
 
 			return ctrl.Result{}, fmt.Errorf("failed to delete workflow from Tinkerbell: %w", err)
 		}
+
+		if r.Watcher != nil {
+			r.Watcher.Untrack(id)
+		}
 	}
 
 	controllerutil.RemoveFinalizer(w, tinkv1alpha1.WorkflowFinalizer)
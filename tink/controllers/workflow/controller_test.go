@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNextBackoffDoublesAndCaps(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &Reconciler{}
+
+	g.Expect(r.nextBackoff("wf", true)).To(Equal(minBackoff))
+	g.Expect(r.nextBackoff("wf", false)).To(Equal(2 * minBackoff))
+	g.Expect(r.nextBackoff("wf", false)).To(Equal(4 * minBackoff))
+
+	// Keep doubling until it would exceed maxBackoff; it must clamp there
+	// instead of overflowing past it.
+	var last time.Duration
+	for i := 0; i < 10; i++ {
+		last = r.nextBackoff("wf", false)
+	}
+
+	g.Expect(last).To(Equal(maxBackoff))
+}
+
+func TestNextBackoffResetsOnChange(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &Reconciler{}
+
+	g.Expect(r.nextBackoff("wf", false)).To(Equal(minBackoff))
+	g.Expect(r.nextBackoff("wf", false)).To(Equal(2 * minBackoff))
+	g.Expect(r.nextBackoff("wf", true)).To(Equal(minBackoff))
+}
+
+func TestNextBackoffIsPerWorkflow(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &Reconciler{}
+
+	g.Expect(r.nextBackoff("a", false)).To(Equal(minBackoff))
+	g.Expect(r.nextBackoff("a", false)).To(Equal(2 * minBackoff))
+	g.Expect(r.nextBackoff("b", false)).To(Equal(minBackoff))
+}
+
+func TestClearBackoffRemovesEntry(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &Reconciler{}
+
+	r.nextBackoff("wf", false)
+	r.clearBackoff("wf")
+
+	g.Expect(r.nextBackoff("wf", false)).To(Equal(minBackoff))
+}
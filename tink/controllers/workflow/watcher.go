@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/protos/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// watcherPollInterval is how frequently the Watcher diffs tink-server's
+// view of in-flight workflows against what it last observed.
+const watcherPollInterval = 10 * time.Second
+
+// observedWorkflow is the slice of a workflow's Tinkerbell-side state that
+// the Watcher diffs against on every poll.
+type observedWorkflow struct {
+	state       workflow.State
+	eventCount  int
+	actionCount int
+}
+
+// Watcher maintains a single, shared view of every in-flight Tinkerbell
+// workflow on behalf of all Reconcile calls in the process, so that
+// tink-server is polled once per workflow rather than once per workflow
+// per reconcile. It enqueues a GenericEvent for a Workflow on its first
+// poll after Track, and after that only when that workflow's state, event
+// count, or action count has actually changed.
+type Watcher struct {
+	client       workflowClient
+	workflowChan chan<- event.GenericEvent
+	log          logr.Logger
+
+	mu       sync.Mutex
+	observed map[string]observedWorkflow
+	keys     map[string]client.ObjectKey
+}
+
+// NewWatcher builds a Watcher that polls c and enqueues changes onto
+// workflowChan.
+func NewWatcher(c workflowClient, workflowChan chan<- event.GenericEvent, log logr.Logger) *Watcher {
+	return &Watcher{
+		client:       c,
+		workflowChan: workflowChan,
+		log:          log,
+		observed:     make(map[string]observedWorkflow),
+		keys:         make(map[string]client.ObjectKey),
+	}
+}
+
+// Track registers id as belonging to key, so that a future change to id is
+// translated into a GenericEvent for the right Workflow object. Reconcile
+// calls this once it knows a workflow's Tinkerbell ID.
+func (wa *Watcher) Track(id string, key client.ObjectKey) {
+	wa.mu.Lock()
+	defer wa.mu.Unlock()
+
+	wa.keys[id] = key
+}
+
+// Untrack removes id from the watcher, so it is no longer polled. Reconcile
+// calls this once a workflow reaches a terminal state or is deleted, so
+// that observed and keys don't grow without bound over the life of the
+// process.
+func (wa *Watcher) Untrack(id string) {
+	wa.mu.Lock()
+	defer wa.mu.Unlock()
+
+	delete(wa.keys, id)
+	delete(wa.observed, id)
+}
+
+// Start runs the poll loop until ctx is cancelled. Its signature matches
+// manager.RunnableFunc, so it can be registered directly with mgr.Add.
+func (wa *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(watcherPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			wa.poll(ctx)
+		}
+	}
+}
+
+func (wa *Watcher) poll(ctx context.Context) {
+	wa.mu.Lock()
+	ids := make([]string, 0, len(wa.keys))
+
+	for id := range wa.keys {
+		ids = append(ids, id)
+	}
+	wa.mu.Unlock()
+
+	for _, id := range ids {
+		wa.pollOne(ctx, id)
+	}
+}
+
+func (wa *Watcher) pollOne(ctx context.Context, id string) {
+	state, err := wa.client.GetState(ctx, id)
+	if err != nil {
+		wa.log.Error(err, "Failed to poll workflow state", "workflowID", id)
+
+		return
+	}
+
+	events, err := wa.client.GetEvents(ctx, id)
+	if err != nil {
+		wa.log.Error(err, "Failed to poll workflow events", "workflowID", id)
+
+		return
+	}
+
+	actions, err := wa.client.GetActions(ctx, id)
+	if err != nil {
+		wa.log.Error(err, "Failed to poll workflow actions", "workflowID", id)
+
+		return
+	}
+
+	current := observedWorkflow{state: state, eventCount: len(events), actionCount: len(actions)}
+
+	wa.mu.Lock()
+	previous, known := wa.observed[id]
+	wa.observed[id] = current
+	key, hasKey := wa.keys[id]
+	wa.mu.Unlock()
+
+	if (known && previous == current) || !hasKey {
+		return
+	}
+
+	wa.workflowChan <- event.GenericEvent{
+		Object: &tinkv1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		},
+	}
+}
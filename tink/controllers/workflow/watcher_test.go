@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/protos/workflow"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeWorkflowClient implements workflowClient with results set directly
+// by the test; only GetState/GetEvents/GetActions are exercised by the
+// Watcher.
+type fakeWorkflowClient struct {
+	mu      sync.Mutex
+	state   workflow.State
+	events  []*workflow.WorkflowActionStatus
+	actions []*workflow.WorkflowAction
+}
+
+func (f *fakeWorkflowClient) Get(context.Context, string) (*workflow.Workflow, error) {
+	return nil, nil
+}
+func (f *fakeWorkflowClient) Create(context.Context, string, string) (string, error) { return "", nil }
+func (f *fakeWorkflowClient) Delete(context.Context, string) error                   { return nil }
+func (f *fakeWorkflowClient) GetMetadata(context.Context, string) ([]byte, error)    { return nil, nil }
+
+func (f *fakeWorkflowClient) GetState(context.Context, string) (workflow.State, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.state, nil
+}
+
+func (f *fakeWorkflowClient) GetEvents(context.Context, string) ([]*workflow.WorkflowActionStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.events, nil
+}
+
+func (f *fakeWorkflowClient) GetActions(context.Context, string) ([]*workflow.WorkflowAction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.actions, nil
+}
+
+func (f *fakeWorkflowClient) set(state workflow.State, eventCount, actionCount int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.state = state
+	f.events = make([]*workflow.WorkflowActionStatus, eventCount)
+	f.actions = make([]*workflow.WorkflowAction, actionCount)
+}
+
+func TestWatcherFiresOnlyOnObservedChange(t *testing.T) {
+	g := NewWithT(t)
+
+	fc := &fakeWorkflowClient{}
+	fc.set(workflow.State_STATE_RUNNING, 1, 1)
+
+	workflowChan := make(chan event.GenericEvent, 10)
+	wa := NewWatcher(fc, workflowChan, logr.DiscardLogger{})
+
+	key := client.ObjectKey{Name: "wf-1", Namespace: "default"}
+	wa.Track("tink-id-1", key)
+
+	// The first observation of a tracked workflow always fires, since
+	// there's nothing to diff against yet.
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(1))
+	<-workflowChan
+
+	// No change in state/event/action counts: no new event.
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(0))
+
+	// Event count changes: fires again.
+	fc.set(workflow.State_STATE_RUNNING, 2, 1)
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(1))
+	<-workflowChan
+
+	// State changes: fires again.
+	fc.set(workflow.State_STATE_SUCCESS, 2, 1)
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(1))
+	<-workflowChan
+}
+
+func TestWatcherUntrackStopsFurtherPolls(t *testing.T) {
+	g := NewWithT(t)
+
+	fc := &fakeWorkflowClient{}
+	fc.set(workflow.State_STATE_RUNNING, 1, 1)
+
+	workflowChan := make(chan event.GenericEvent, 10)
+	wa := NewWatcher(fc, workflowChan, logr.DiscardLogger{})
+
+	key := client.ObjectKey{Name: "wf-1", Namespace: "default"}
+	wa.Track("tink-id-1", key)
+
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(1))
+	<-workflowChan
+
+	wa.Untrack("tink-id-1")
+
+	fc.set(workflow.State_STATE_SUCCESS, 5, 5)
+	wa.poll(context.Background())
+	g.Expect(workflowChan).To(HaveLen(0))
+}
@@ -17,18 +17,15 @@ limitations under the License.
 package client_test
 
 import (
-	"crypto/x509"
-	"io/ioutil"
-	"net/http"
 	"os"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/tinkclient"
 	"github.com/tinkerbell/tink/protos/hardware"
 	"github.com/tinkerbell/tink/protos/template"
 	"github.com/tinkerbell/tink/protos/workflow"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 func generateTemplate(name, data string) *template.WorkflowTemplate {
@@ -51,39 +48,20 @@ func realConn(t *testing.T) *grpc.ClientConn {
 		t.Skip("Skipping live client tests because TINKERBELL_GRPC_AUTHORITY is not set.")
 	}
 
-	resp, err := http.Get(certURL) //nolint:noctx
-	g.Expect(err).NotTo(HaveOccurred())
-
-	defer resp.Body.Close() //nolint:errcheck
-
-	certs, err := ioutil.ReadAll(resp.Body)
-	g.Expect(err).NotTo(HaveOccurred())
-
-	cp := x509.NewCertPool()
-	ok = cp.AppendCertsFromPEM(certs)
-	g.Expect(ok).To(BeTrue())
-
-	creds := credentials.NewClientTLSFromCert(cp, "tink-server")
-	conn, err := grpc.Dial(grpcAuthority, grpc.WithTransportCredentials(creds))
+	conn, err := tinkclient.Dial(certURL, grpcAuthority)
 	g.Expect(err).NotTo(HaveOccurred())
 
 	return conn
 }
 
 func realTemplateClient(t *testing.T) template.TemplateServiceClient {
-	conn := realConn(t)
-
-	return template.NewTemplateServiceClient(conn)
+	return tinkclient.TemplateClient(realConn(t))
 }
 
 func realWorkflowClient(t *testing.T) workflow.WorkflowServiceClient {
-	conn := realConn(t)
-
-	return workflow.NewWorkflowServiceClient(conn)
+	return tinkclient.WorkflowClient(realConn(t))
 }
 
 func realHardwareClient(t *testing.T) hardware.HardwareServiceClient {
-	conn := realConn(t)
-
-	return hardware.NewHardwareServiceClient(conn)
+	return tinkclient.HardwareClient(realConn(t))
 }
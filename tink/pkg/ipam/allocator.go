@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrPoolNotFound is returned when Allocate is given a pool name that
+// doesn't exist.
+var ErrPoolNotFound = errors.New("ip pool not found")
+
+// Allocation is an address handed out to a MAC, together with the
+// surrounding network configuration needed to populate a Hardware
+// interface.
+type Allocation struct {
+	MAC     string
+	IP      string
+	Netmask string
+	Gateway string
+	DNS     []string
+}
+
+// Allocator hands out and reclaims addresses from TinkerbellIPPool CRDs.
+// Unlike the process-memory ipGetter it replaces, state is persisted on
+// the pool's status, so allocations survive a controller restart.
+type Allocator struct {
+	Client client.Client
+}
+
+// NewAllocator returns an Allocator backed by c.
+func NewAllocator(c client.Client) *Allocator {
+	return &Allocator{Client: c}
+}
+
+// Allocate returns the address reserved for mac in poolName, allocating a
+// new one if mac has never been seen. Allocating the same mac twice is a
+// no-op that returns the existing address.
+func (a *Allocator) Allocate(ctx context.Context, poolName, mac string) (Allocation, error) {
+	var result Allocation
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool := &tinkv1alpha1.TinkerbellIPPool{}
+		if err := a.Client.Get(ctx, client.ObjectKey{Name: poolName}, pool); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("%w: %s", ErrPoolNotFound, poolName)
+			}
+
+			return fmt.Errorf("failed to get ip pool: %w", err)
+		}
+
+		netmask, err := Netmask(pool.Spec.CIDR)
+		if err != nil {
+			return err
+		}
+
+		if ip, ok := existingAllocation(pool, mac); ok {
+			result = Allocation{MAC: mac, IP: ip, Netmask: netmask, Gateway: pool.Spec.Gateway, DNS: pool.Spec.DNS}
+
+			return nil
+		}
+
+		ip, reserved := pool.Spec.Reservations[mac]
+		if reserved {
+			if owner, ok := allocatedTo(pool, ip); ok && owner != mac {
+				return fmt.Errorf("ip %s reserved for mac %s is already allocated to %s", ip, mac, owner)
+			}
+		} else {
+			ip, err = NextAddress(pool)
+			if err != nil {
+				return err
+			}
+		}
+
+		pool.Status.Allocated = append(pool.Status.Allocated, tinkv1alpha1.Allocation{MAC: mac, IP: ip})
+
+		capacity, err := Capacity(pool)
+		if err != nil {
+			return err
+		}
+
+		pool.Status.Available = capacity - unreservedAllocations(pool)
+
+		if err := a.Client.Status().Update(ctx, pool); err != nil {
+			return fmt.Errorf("failed to update ip pool status: %w", err)
+		}
+
+		result = Allocation{MAC: mac, IP: ip, Netmask: netmask, Gateway: pool.Spec.Gateway, DNS: pool.Spec.DNS}
+
+		return nil
+	})
+	if err != nil {
+		return Allocation{}, err
+	}
+
+	return result, nil
+}
+
+// Release returns mac's address to whichever pool it was allocated from.
+// It is a no-op if mac has no recorded allocation.
+func (a *Allocator) Release(ctx context.Context, mac string) error {
+	pools := &tinkv1alpha1.TinkerbellIPPoolList{}
+	if err := a.Client.List(ctx, pools); err != nil {
+		return fmt.Errorf("failed to list ip pools: %w", err)
+	}
+
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		if _, ok := existingAllocation(pool, mac); !ok {
+			continue
+		}
+
+		return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := &tinkv1alpha1.TinkerbellIPPool{}
+			if err := a.Client.Get(ctx, client.ObjectKey{Name: pool.Name}, latest); err != nil {
+				return fmt.Errorf("failed to get ip pool: %w", err)
+			}
+
+			remaining := latest.Status.Allocated[:0]
+
+			for _, alloc := range latest.Status.Allocated {
+				if alloc.MAC == mac {
+					continue
+				}
+
+				remaining = append(remaining, alloc)
+			}
+
+			latest.Status.Allocated = remaining
+
+			capacity, err := Capacity(latest)
+			if err != nil {
+				return err
+			}
+
+			latest.Status.Available = capacity - unreservedAllocations(latest)
+
+			if err := a.Client.Status().Update(ctx, latest); err != nil {
+				return fmt.Errorf("failed to update ip pool status: %w", err)
+			}
+
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func existingAllocation(pool *tinkv1alpha1.TinkerbellIPPool, mac string) (string, bool) {
+	for _, a := range pool.Status.Allocated {
+		if a.MAC == mac {
+			return a.IP, true
+		}
+	}
+
+	return "", false
+}
+
+// unreservedAllocations counts the entries of pool.Status.Allocated other
+// than a MAC holding its own Spec.Reservations address, since Capacity has
+// already subtracted those.
+func unreservedAllocations(pool *tinkv1alpha1.TinkerbellIPPool) int {
+	n := 0
+
+	for _, a := range pool.Status.Allocated {
+		if pool.Spec.Reservations[a.MAC] != a.IP {
+			n++
+		}
+	}
+
+	return n
+}
+
+// allocatedTo returns the MAC that ip is currently allocated to, if any.
+func allocatedTo(pool *tinkv1alpha1.TinkerbellIPPool, ip string) (string, bool) {
+	for _, a := range pool.Status.Allocated {
+		if a.IP == ip {
+			return a.MAC, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipam allocates and releases addresses for Tinkerbell Hardware
+// from TinkerbellIPPool CRDs, replacing the process-memory-only ipGetter
+// that could not survive a controller restart or hand out IPv6 addresses.
+package ipam
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+)
+
+// ErrPoolExhausted is returned when a pool has no free addresses left.
+var ErrPoolExhausted = errors.New("ip pool exhausted")
+
+// NextAddress walks pool's CIDR in order, via big.Int so it works for
+// both IPv4 and IPv6, and returns the first address that is not the
+// network/broadcast address, the gateway, excluded by ExcludeRanges,
+// reserved for a specific MAC in Spec.Reservations, or already present in
+// pool.Status.Allocated. Reservations are excluded unconditionally (even
+// though the reserved MAC hasn't called Allocate yet) so that a
+// not-yet-seen device's address is never handed out to someone else.
+func NextAddress(pool *tinkv1alpha1.TinkerbellIPPool) (string, error) {
+	ip, network, err := net.ParseCIDR(pool.Spec.CIDR)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cidr %q: %w", pool.Spec.CIDR, err)
+	}
+
+	excluded, err := parseExcludeRanges(pool.Spec.ExcludeRanges)
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]struct{}, len(pool.Status.Allocated)+len(pool.Spec.Reservations))
+	for _, a := range pool.Status.Allocated {
+		taken[a.IP] = struct{}{}
+	}
+
+	for _, reservedIP := range pool.Spec.Reservations {
+		taken[reservedIP] = struct{}{}
+	}
+
+	isV4 := ip.To4() != nil
+
+	start := ipToInt(network.IP)
+	end := broadcastInt(network)
+
+	// The network address itself is never handed out.
+	for cur := new(big.Int).Add(start, big.NewInt(1)); cur.Cmp(end) < 0; cur.Add(cur, big.NewInt(1)) {
+		candidate := intToIP(cur, isV4)
+
+		switch {
+		case candidate.String() == pool.Spec.Gateway:
+		case isExcluded(candidate, excluded):
+		default:
+			if _, ok := taken[candidate.String()]; !ok {
+				return candidate.String(), nil
+			}
+		}
+	}
+
+	return "", ErrPoolExhausted
+}
+
+func parseExcludeRanges(ranges []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(ranges))
+
+	for _, r := range ranges {
+		_, n, err := net.ParseCIDR(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse exclude range %q: %w", r, err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+func isExcluded(ip net.IP, excluded []*net.IPNet) bool {
+	for _, n := range excluded {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// broadcastInt returns the last address of network as a *big.Int. For
+// IPv6, where there is no broadcast address, this is simply the final
+// address in the range.
+func broadcastInt(network *net.IPNet) *big.Int {
+	start := ipToInt(network.IP)
+
+	ones, bits := network.Mask.Size()
+	hostBits := uint(bits - ones)
+
+	size := new(big.Int).Lsh(big.NewInt(1), hostBits)
+	size.Sub(size, big.NewInt(1))
+
+	return start.Add(start, size)
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		return new(big.Int).SetBytes(v4)
+	}
+
+	return new(big.Int).SetBytes(ip.To16())
+}
+
+func intToIP(i *big.Int, isV4 bool) net.IP {
+	size := 16
+	if isV4 {
+		size = 4
+	}
+
+	b := i.Bytes()
+	buf := make([]byte, size)
+	copy(buf[size-len(b):], b)
+
+	return net.IP(buf)
+}
+
+// Netmask returns the dotted-decimal (or IPv6) netmask for cidr.
+func Netmask(cidr string) (string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cidr %q: %w", cidr, err)
+	}
+
+	return net.IP(network.Mask).String(), nil
+}
+
+// Capacity returns the number of addresses pool can ever hand out: the
+// usable host range (CIDR size minus the network and broadcast
+// addresses) minus the gateway, ExcludeRanges, and Reservations. It does
+// not look at Status.Allocated; callers subtract the allocations that are
+// not a MAC's own reservation themselves to get the number currently free.
+//
+// ExcludeRanges are assumed not to overlap each other or the gateway; if
+// they do, Capacity undercounts rather than overcounts. Allocate does not
+// consult Capacity, so this affects only the reported Status.Available.
+func Capacity(pool *tinkv1alpha1.TinkerbellIPPool) (int, error) {
+	_, network, err := net.ParseCIDR(pool.Spec.CIDR)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse cidr %q: %w", pool.Spec.CIDR, err)
+	}
+
+	start := ipToInt(network.IP)
+	end := broadcastInt(network)
+
+	// The usable range excludes the network and broadcast addresses.
+	total := new(big.Int).Sub(end, start)
+	total.Sub(total, big.NewInt(1))
+
+	if pool.Spec.Gateway != "" {
+		total.Sub(total, big.NewInt(1))
+	}
+
+	excluded, err := parseExcludeRanges(pool.Spec.ExcludeRanges)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range excluded {
+		ones, bits := n.Mask.Size()
+		total.Sub(total, new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)))
+	}
+
+	total.Sub(total, big.NewInt(int64(len(pool.Spec.Reservations))))
+
+	if total.Sign() < 0 {
+		return 0, nil
+	}
+
+	return int(total.Int64()), nil
+}
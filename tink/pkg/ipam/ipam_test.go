@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam_test
+
+import (
+	"testing"
+
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/ipam"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNextAddressSkipsGatewayAndAllocated(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:    "192.0.2.0/29",
+			Gateway: "192.0.2.1",
+		},
+		Status: tinkv1alpha1.TinkerbellIPPoolStatus{
+			Allocated: []tinkv1alpha1.Allocation{{MAC: "aa:aa:aa:aa:aa:aa", IP: "192.0.2.2"}},
+		},
+	}
+
+	ip, err := ipam.NextAddress(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ip).To(Equal("192.0.2.3"))
+}
+
+func TestNextAddressSkipsExcludeRanges(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:          "192.0.2.0/29",
+			Gateway:       "192.0.2.1",
+			ExcludeRanges: []string{"192.0.2.2/31"},
+		},
+	}
+
+	ip, err := ipam.NextAddress(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ip).To(Equal("192.0.2.4"))
+}
+
+func TestNextAddressSkipsReservations(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:    "192.0.2.0/29",
+			Gateway: "192.0.2.1",
+			Reservations: map[string]string{
+				"aa:aa:aa:aa:aa:aa": "192.0.2.2",
+			},
+		},
+	}
+
+	// 192.0.2.2 is reserved for a MAC that hasn't allocated yet, so it
+	// must not be handed out to anyone else.
+	ip, err := ipam.NextAddress(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ip).To(Equal("192.0.2.3"))
+}
+
+func TestNextAddressIPv6(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:    "fd00::/126",
+			Gateway: "fd00::1",
+		},
+	}
+
+	ip, err := ipam.NextAddress(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ip).To(Equal("fd00::2"))
+}
+
+func TestNextAddressExhausted(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:    "192.0.2.0/30",
+			Gateway: "192.0.2.1",
+		},
+		Status: tinkv1alpha1.TinkerbellIPPoolStatus{
+			Allocated: []tinkv1alpha1.Allocation{{MAC: "aa:aa:aa:aa:aa:aa", IP: "192.0.2.2"}},
+		},
+	}
+
+	_, err := ipam.NextAddress(pool)
+	g.Expect(err).To(MatchError(ipam.ErrPoolExhausted))
+}
+
+func TestCapacityAccountsForGatewayExcludesAndReservations(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &tinkv1alpha1.TinkerbellIPPool{
+		Spec: tinkv1alpha1.TinkerbellIPPoolSpec{
+			CIDR:          "192.0.2.0/29",
+			Gateway:       "192.0.2.1",
+			ExcludeRanges: []string{"192.0.2.2/31"},
+			Reservations: map[string]string{
+				"aa:aa:aa:aa:aa:aa": "192.0.2.6",
+			},
+		},
+	}
+
+	// /29 has 8 addresses, minus network (.0) and broadcast (.7) leaves 6
+	// usable, minus the gateway (.1), the /31 exclude range (.2-.3), and
+	// the one reservation (.6) leaves 2 (.4 and .5).
+	capacity, err := ipam.Capacity(pool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(capacity).To(Equal(2))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package templates renders tink workflow templates with values sourced
+// from a Workflow CR, rather than leaving callers to string-format YAML
+// by hand before it reaches GenerateTemplate.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TemplateSpec mirrors the version/global_timeout/tasks/actions shape
+// that tink's workflow templates are written in.
+type TemplateSpec struct {
+	Version       string `yaml:"version"`
+	GlobalTimeout int    `yaml:"global_timeout"`
+	Tasks         []Task `yaml:"tasks"`
+}
+
+// Task is a named group of Actions run on a single worker.
+type Task struct {
+	Name    string   `yaml:"name"`
+	Worker  string   `yaml:"worker"`
+	Actions []Action `yaml:"actions"`
+}
+
+// Action is a single step within a Task.
+type Action struct {
+	Name        string            `yaml:"name"`
+	Image       string            `yaml:"image"`
+	Timeout     int64             `yaml:"timeout"`
+	Command     []string          `yaml:"command,omitempty"`
+	OnTimeout   []string          `yaml:"on_timeout,omitempty"`
+	OnFailure   []string          `yaml:"on_failure,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+}
+
+// placeholderPattern matches the Go text/template placeholders tink
+// templates use to reference per-workflow values, e.g. "{{.device_1}}".
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\.([A-Za-z0-9_]+)\s*\}\}`)
+
+// Parse decodes raw into a typed TemplateSpec. raw is expected to have
+// already been rendered, since Parse has no notion of template
+// placeholders.
+func Parse(raw string) (*TemplateSpec, error) {
+	spec := &TemplateSpec{}
+	if err := yaml.Unmarshal([]byte(raw), spec); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return spec, nil
+}
+
+// Placeholders returns the de-duplicated set of "{{.foo}}"-style
+// placeholder names referenced anywhere in raw.
+func Placeholders(raw string) []string {
+	matches := placeholderPattern.FindAllStringSubmatch(raw, -1)
+
+	seen := make(map[string]struct{}, len(matches))
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		name := m[1]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// MissingParameters returns the placeholders referenced in raw that have
+// no entry in params.
+func MissingParameters(raw string, params map[string]string) []string {
+	var missing []string
+
+	for _, name := range Placeholders(raw) {
+		if _, ok := params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// Render substitutes params into raw's "{{.foo}}" placeholders. Callers
+// should check MissingParameters first: Render itself will fail a
+// placeholder with no bound value rather than silently leaving it blank.
+func Render(raw string, params map[string]string) (string, error) {
+	tmpl, err := template.New("workflow").Option("missingkey=error").Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/templates"
+
+	. "github.com/onsi/gomega"
+)
+
+const rawTemplate = `version: "0.1"
+name: hello_world_workflow
+global_timeout: 600
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+      - name: "hello_world"
+        image: hello-world
+        timeout: 60`
+
+func TestMissingParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(templates.MissingParameters(rawTemplate, nil)).To(ConsistOf("device_1"))
+	g.Expect(templates.MissingParameters(rawTemplate, map[string]string{"device_1": "worker-1"})).To(BeEmpty())
+}
+
+func TestRenderFailsOnMissingParameter(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := templates.Render(rawTemplate, nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRenderAndParse(t *testing.T) {
+	g := NewWithT(t)
+
+	rendered, err := templates.Render(rawTemplate, map[string]string{"device_1": "worker-1"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rendered).To(ContainSubstring("worker-1"))
+
+	spec, err := templates.Parse(rendered)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.Version).To(Equal("0.1"))
+	g.Expect(spec.GlobalTimeout).To(Equal(600))
+	g.Expect(spec.Tasks).To(HaveLen(1))
+	g.Expect(spec.Tasks[0].Worker).To(Equal("worker-1"))
+	g.Expect(spec.Tasks[0].Actions).To(HaveLen(1))
+	g.Expect(spec.Tasks[0].Actions[0].Image).To(Equal("hello-world"))
+}
+
+func TestParseRejectsInvalidYAML(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := templates.Parse("not: [valid")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tinkclient dials a live tink-server and hands back its gRPC
+// service clients. It exists so both tests and operator tooling (such as
+// cmd/tink-fixtures) can reach a real Tinkerbell stack without each
+// reimplementing TLS setup.
+package tinkclient
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/tinkerbell/tink/protos/hardware"
+	"github.com/tinkerbell/tink/protos/template"
+	"github.com/tinkerbell/tink/protos/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Dial fetches tink-server's CA certificate from certURL and opens a TLS
+// gRPC connection to grpcAuthority.
+func Dial(certURL, grpcAuthority string) (*grpc.ClientConn, error) {
+	resp, err := http.Get(certURL) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tink-server cert: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	certs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tink-server cert: %w", err)
+	}
+
+	cp := x509.NewCertPool()
+	if ok := cp.AppendCertsFromPEM(certs); !ok {
+		return nil, fmt.Errorf("failed to parse tink-server cert from %s", certURL)
+	}
+
+	creds := credentials.NewClientTLSFromCert(cp, "tink-server")
+
+	conn, err := grpc.Dial(grpcAuthority, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial tink-server at %s: %w", grpcAuthority, err)
+	}
+
+	return conn, nil
+}
+
+// TemplateClient returns a TemplateServiceClient bound to conn.
+func TemplateClient(conn *grpc.ClientConn) template.TemplateServiceClient {
+	return template.NewTemplateServiceClient(conn)
+}
+
+// WorkflowClient returns a WorkflowServiceClient bound to conn.
+func WorkflowClient(conn *grpc.ClientConn) workflow.WorkflowServiceClient {
+	return workflow.NewWorkflowServiceClient(conn)
+}
+
+// HardwareClient returns a HardwareServiceClient bound to conn.
+func HardwareClient(conn *grpc.ClientConn) hardware.HardwareServiceClient {
+	return hardware.NewHardwareServiceClient(conn)
+}
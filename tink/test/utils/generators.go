@@ -23,6 +23,8 @@ import (
 	"net"
 	"sync"
 
+	tinkv1alpha1 "github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/cluster-api-provider-tinkerbell/tink/pkg/ipam"
 	"github.com/tinkerbell/tink/protos/hardware"
 	"github.com/tinkerbell/tink/protos/template"
 )
@@ -47,49 +49,54 @@ var testCIDRs = [...]string{
 	"203.0.113.0/24",
 }
 
-var IPGetter = ipGetter{
-	addresses: make(map[string]string),
-}
+// ipPools tracks, per-CIDR, the fixture pool that fixtureIPGetter draws
+// addresses from. It plays the same role that TinkerbellIPPool.Status
+// plays for the real ipam.Allocator, just held in process memory since
+// test fixtures don't need to survive a restart.
+var ipPools = struct {
+	sync.Mutex
+	byCIDR map[string]*tinkv1alpha1.TinkerbellIPPool
+}{byCIDR: make(map[string]*tinkv1alpha1.TinkerbellIPPool)}
+
+// nextAddressFromCIDR returns the next unused address in cidr, along with
+// its netmask and gateway. It delegates the actual walk to
+// ipam.NextAddress so fixtures exercise the same IPv4/IPv6-safe big.Int
+// arithmetic as the real allocator, rather than the old byte-increment
+// that couldn't skip addresses or handle IPv6.
+func nextAddressFromCIDR(cidr string) (string, string, string, error) {
+	ipPools.Lock()
+	defer ipPools.Unlock()
+
+	pool, ok := ipPools.byCIDR[cidr]
+	if !ok {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to parse cidr: %w", err)
+		}
 
-type ipGetter struct {
-	addresses map[string]string
-	lock      sync.Mutex
-}
+		gw := make(net.IP, len(network.IP))
+		copy(gw, network.IP)
+		gw[len(gw)-1]++
 
-func (i *ipGetter) nextAddressFromCIDR(cidr string) (string, string, string, error) {
-	i.lock.Lock()
-	defer i.lock.Unlock()
+		pool = &tinkv1alpha1.TinkerbellIPPool{
+			Spec: tinkv1alpha1.TinkerbellIPPoolSpec{CIDR: cidr, Gateway: gw.String()},
+		}
+		ipPools.byCIDR[cidr] = pool
+	}
 
-	_, network, err := net.ParseCIDR(cidr)
+	netmask, err := ipam.Netmask(cidr)
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to parse cidr: %w", err)
+		return "", "", "", err
 	}
 
-	netMask := net.IP(network.Mask).String()
-
-	// Use the first available address as the gateway address
-	gw := make(net.IP, len(network.IP))
-	copy(gw, network.IP)
-	gw[len(gw)-1]++
-	gateway := gw.String()
-
-	// Attempt to get the last address used, otherwise use the
-	// gateway address as the starting point
-	lastAddress, ok := i.addresses[cidr]
-	if !ok {
-		lastAddress = gateway
+	ip, err := ipam.NextAddress(pool)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get next address from cidr: %w", err)
 	}
 
-	// Get the next IP by incrementing lastAddress
-	nextIP := net.ParseIP(lastAddress)
-	nextIP[len(nextIP)-1]++
+	pool.Status.Allocated = append(pool.Status.Allocated, tinkv1alpha1.Allocation{IP: ip})
 
-	ip := nextIP.String()
-
-	// Store the last address
-	i.addresses[cidr] = ip
-
-	return ip, netMask, gateway, nil
+	return ip, netmask, pool.Spec.Gateway, nil
 }
 
 var MACGenerator = macGenerator{
@@ -135,15 +142,21 @@ func GenerateTemplate(name, data string) *template.WorkflowTemplate {
 	}
 }
 
-func GenerateHardware(numInterfaces int) (*hardware.Hardware, error) {
+// GenerateHardware builds a Hardware fixture with numInterfaces network
+// interfaces. If cidr is empty, each interface draws its address from a
+// round-robin over testCIDRs; otherwise every interface draws from cidr.
+func GenerateHardware(numInterfaces int, cidr string) (*hardware.Hardware, error) {
 	hw := &hardware.Hardware{
 		Network: &hardware.Hardware_Network{},
 	}
 
 	for i := 0; i < numInterfaces; i++ {
-		cidr := testCIDRs[i%len(testCIDRs)]
+		ifaceCIDR := cidr
+		if ifaceCIDR == "" {
+			ifaceCIDR = testCIDRs[i%len(testCIDRs)]
+		}
 
-		ni, err := GenerateHardwareInterface(cidr)
+		ni, err := GenerateHardwareInterface(ifaceCIDR)
 		if err != nil {
 			return nil, err
 		}
@@ -164,7 +177,7 @@ func GenerateHardwareInterface(cidr string) (*hardware.Hardware_Network_Interfac
 		cidr = testCIDRs[i.Int64()]
 	}
 
-	ip, netmask, gateway, err := IPGetter.nextAddressFromCIDR(cidr)
+	ip, netmask, gateway, err := nextAddressFromCIDR(cidr)
 	if err != nil {
 		return nil, err
 	}